@@ -0,0 +1,113 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/external-initiator/store"
+	"github.com/smartcontractkit/external-initiator/subscriber"
+)
+
+func TestEthManager_Backfill_ChunksAndPersists(t *testing.T) {
+	e := CreateEthManager(subscriber.RPC, store.EthSubscription{
+		LastProcessedBlock: 0x10,
+		BackfillBatchSize:  2,
+	})
+
+	// First call learns the replay target.
+	req := e.GetTriggerJson()
+	var headReq jsonrpcMessage
+	if err := json.Unmarshal(req, &headReq); err != nil || headReq.Method != "eth_blockNumber" {
+		t.Fatalf("expected eth_blockNumber request, got %s", req)
+	}
+	if _, ok := e.ParseResponse(wrapRpcResult(mustMarshal(t, "0x14"))); !ok {
+		t.Fatalf("failed to parse head response")
+	}
+
+	// Batch 1: [0x11, 0x12] (window size 2).
+	req = e.GetTriggerJson()
+	var logsReq jsonrpcMessage
+	if err := json.Unmarshal(req, &logsReq); err != nil || logsReq.Method != "eth_getLogs" {
+		t.Fatalf("expected eth_getLogs request, got %s", req)
+	}
+	var params []map[string]interface{}
+	_ = json.Unmarshal(logsReq.Params, &params)
+	if params[0]["fromBlock"] != "0x11" || params[0]["toBlock"] != "0x12" {
+		t.Fatalf("unexpected batch range: %+v", params[0])
+	}
+
+	events, ok := e.ParseResponse(wrapRpcResult(mustMarshal(t, []ethLogResponse{{BlockNumber: "0x11"}})))
+	if !ok || len(events) != 1 {
+		t.Fatalf("expected 1 event from batch 1, got %d (ok=%v)", len(events), ok)
+	}
+	if e.LastProcessedBlock() != 0x12 {
+		t.Fatalf("expected LastProcessedBlock to advance to 0x12, got %#x", e.LastProcessedBlock())
+	}
+
+	// Batch 2: [0x13, 0x14] - reaches the target, ends replay.
+	req = e.GetTriggerJson()
+	_ = json.Unmarshal(req, &logsReq)
+	_ = json.Unmarshal(logsReq.Params, &params)
+	if params[0]["toBlock"] != "0x14" {
+		t.Fatalf("expected final batch to be capped at the target, got %+v", params[0])
+	}
+	if _, ok := e.ParseResponse(wrapRpcResult(mustMarshal(t, []ethLogResponse{}))); !ok {
+		t.Fatalf("failed to parse batch 2 response")
+	}
+	if e.LastProcessedBlock() != 0x14 {
+		t.Fatalf("expected LastProcessedBlock to reach the target, got %#x", e.LastProcessedBlock())
+	}
+
+	// Replay is done; the manager should fall through to live polling,
+	// picking up right after the replay target rather than "latest" at
+	// install time - otherwise blocks mined during the (serial, chunked)
+	// backfill would be silently skipped.
+	req = e.GetTriggerJson()
+	var liveReq jsonrpcMessage
+	if err := json.Unmarshal(req, &liveReq); err != nil || liveReq.Method != "eth_newFilter" {
+		t.Fatalf("expected manager to switch to live eth_newFilter polling, got %s", req)
+	}
+	var liveParams []map[string]interface{}
+	_ = json.Unmarshal(liveReq.Params, &liveParams)
+	if liveParams[0]["fromBlock"] != "0x15" {
+		t.Fatalf("expected live filter to start right after the replay target (0x15), got %+v", liveParams[0])
+	}
+}
+
+func TestEthManager_Backfill_HalvesWindowOnTooManyResults(t *testing.T) {
+	e := CreateEthManager(subscriber.RPC, store.EthSubscription{
+		LastProcessedBlock: 1,
+		BackfillBatchSize:  1000,
+	})
+
+	e.GetTriggerJson()
+	e.ParseResponse(wrapRpcResult(mustMarshal(t, "0x3e8")))
+
+	req := e.GetTriggerJson()
+	var logsReq jsonrpcMessage
+	_ = json.Unmarshal(req, &logsReq)
+
+	errResp, _ := json.Marshal(jsonrpcMessage{
+		Version: "2.0",
+		ID:      logsReq.ID,
+		Error:   &jsonrpcError{Message: "query returned more than 10000 results"},
+	})
+	if _, ok := e.ParseResponse(errResp); !ok {
+		t.Fatalf("expected too-many-results error to be handled, not rejected")
+	}
+	if e.backfillWindow != 500 {
+		t.Fatalf("expected backfill window to halve to 500, got %d", e.backfillWindow)
+	}
+	if e.backfillCursor != 2 {
+		t.Fatalf("expected cursor to stay put for a retry, got %d", e.backfillCursor)
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) json.RawMessage {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return b
+}
@@ -8,13 +8,128 @@ import (
 	"github.com/smartcontractkit/external-initiator/subscriber"
 	"math/big"
 	"strconv"
+	"strings"
+	"time"
 )
 
 const ETH = "ethereum"
 
+// defaultPollInterval is how often the RPC poller should call GetTriggerJson
+// when the subscription's config doesn't specify one.
+const defaultPollInterval = 15 * time.Second
+
+// defaultBackfillWindow is how many blocks a single eth_getLogs backfill
+// batch covers when the subscription's config doesn't specify one.
+const defaultBackfillWindow = 1000
+
+// blockTags are the symbolic (non-numeric) block tags a node may accept in
+// place of a hex block number. "accepted" is Avalanche C-Chain/coreth's
+// analogue of "finalized", and "finalized"/"safe" are the post-merge geth
+// tags; none of these can be parsed with strconv.ParseInt, so the manager
+// has to track their underlying height itself.
+var blockTags = map[string]bool{
+	"latest":    true,
+	"pending":   true,
+	"earliest":  true,
+	"accepted":  true,
+	"finalized": true,
+	"safe":      true,
+}
+
+func isBlockTag(s string) bool {
+	return blockTags[s]
+}
+
+// filterPhase tracks where an RPC subscription is in the
+// eth_newFilter/eth_getFilterChanges lifecycle.
+type filterPhase int
+
+const (
+	// filterPhaseInstall means we have no live server-side filter and the
+	// next request should be eth_newFilter.
+	filterPhaseInstall filterPhase = iota
+	// filterPhasePoll means we hold a filter ID and should poll it with
+	// eth_getFilterChanges.
+	filterPhasePoll
+	// filterPhaseRecover means our filter expired (the node returned
+	// "filter not found", most likely because we didn't poll within its
+	// deadline) and we need one eth_getLogs call to bridge the gap before
+	// installing a fresh filter.
+	filterPhaseRecover
+)
+
 type EthManager struct {
 	fq filterQuery
 	p  subscriber.Type
+
+	// fromTag is the symbolic tag (e.g. "finalized") this subscription was
+	// configured with, or "" if it tracks plain "latest" semantics.
+	fromTag string
+	// confirmations is how many blocks below the tag's reported height a
+	// log must be before we'll stop tracking it via the tag and pin
+	// FromBlock to a concrete block number, reducing reorg-driven
+	// duplicate initiator runs.
+	confirmations int64
+	// tagHeight is the most recent height the manager observed for fromTag,
+	// populated via GetTagHeightJson/ParseTagHeightResponse.
+	tagHeight *big.Int
+	// highWaterBlock is the highest block number we've emitted a log from
+	// while FromBlock is still pinned to fromTag.
+	highWaterBlock *big.Int
+
+	// phase, filterID and awaitingFilterID track the RPC server-side filter
+	// lifecycle: install the filter once, then poll it with its ID instead
+	// of re-scanning eth_getLogs from a stored FromBlock on every poll.
+	phase            filterPhase
+	filterID         string
+	awaitingFilterID bool
+	// pollInterval is how often the poller should call GetTriggerJson.
+	pollInterval time.Duration
+
+	// minConfirmations and reorgGuard implement reorg-safe delivery: logs
+	// are buffered until the head has moved minConfirmations blocks past
+	// them, and replayed/removed logs are deduplicated. Nil reorgGuard
+	// (the MinConfirmations == 0 default) preserves the old behavior of
+	// emitting logs as soon as they're seen.
+	minConfirmations int64
+	reorgGuard       *ReorgGuard
+	// head is the most recent chain head height, populated via
+	// GetHeadBlockJson/ParseHeadBlockResponse and used to decide when
+	// buffered logs in reorgGuard are safe to release.
+	head *big.Int
+
+	// kind and predicate configure pending-transaction mode
+	// (store.EthSubscriptionKindPendingTx): predicate filters the full
+	// transaction objects the node returns so the initiator only dispatches
+	// a job run for transactions the operator actually cares about.
+	kind      store.EthSubscriptionKind
+	predicate txPredicate
+	// pendingHashes holds hashes returned by eth_getFilterChanges on an
+	// RPC pending-tx filter that still need hydrating via
+	// eth_getTransactionByHash before they can be filtered and emitted.
+	pendingHashes []string
+
+	// backfilling and the fields below drive the historical replay that
+	// runs before the manager switches over to live WS/RPC polling. See
+	// getBackfillTriggerJson/parseBackfillResponse.
+	backfilling bool
+	// backfillCursor is the next block the manager needs logs for.
+	backfillCursor int64
+	// backfillWindow is how many blocks the next eth_getLogs batch covers;
+	// it's halved (down to a floor of 1) whenever a node rejects a batch
+	// for returning too many results.
+	backfillWindow int64
+	// backfillWindowEnd is the "toBlock" of the batch currently in flight,
+	// so parseBackfillResponse knows how far backfillCursor should advance
+	// on success.
+	backfillWindowEnd int64
+	// backfillTarget is "latest" as observed when backfill started; replay
+	// stops once backfillCursor passes it.
+	backfillTarget *int64
+	// lastProcessedBlock is the highest block backfill has successfully
+	// replayed through. The poller should persist it after every batch via
+	// LastProcessedBlock, so a crash mid-backfill resumes cleanly.
+	lastProcessedBlock int64
 }
 
 func CreateEthManager(p subscriber.Type, config store.EthSubscription) EthManager {
@@ -33,42 +148,234 @@ func CreateEthManager(p subscriber.Type, config store.EthSubscription) EthManage
 	}
 	topics = append(topics, t)
 
+	fromBlock := ""
+	if isBlockTag(config.FromTag) {
+		fromBlock = config.FromTag
+	}
+
+	pollInterval := config.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+
+	var reorgGuard *ReorgGuard
+	if config.MinConfirmations > 0 {
+		reorgGuard = NewReorgGuard(config.MinConfirmations)
+	}
+
+	kind := config.Kind
+	if kind == "" {
+		kind = store.EthSubscriptionKindLogs
+	}
+
+	backfillWindow := config.BackfillBatchSize
+	if backfillWindow <= 0 {
+		backfillWindow = defaultBackfillWindow
+	}
+
 	return EthManager{
 		fq: filterQuery{
 			Addresses: addresses,
 			Topics:    topics,
+			FromBlock: fromBlock,
 		},
-		p: p,
+		p:                p,
+		fromTag:          config.FromTag,
+		confirmations:    config.TagSettleConfirmations,
+		pollInterval:     pollInterval,
+		minConfirmations: config.MinConfirmations,
+		reorgGuard:       reorgGuard,
+		kind:             kind,
+		predicate:        newTxPredicate(config.Addresses, config.Selectors),
+		// Historical replay only makes sense for log subscriptions - nodes
+		// don't keep a historical record of pending transactions to replay.
+		backfilling:        config.LastProcessedBlock > 0 && kind == store.EthSubscriptionKindLogs,
+		backfillCursor:     config.LastProcessedBlock + 1,
+		backfillWindow:     backfillWindow,
+		lastProcessedBlock: config.LastProcessedBlock,
+	}
+}
+
+// PollInterval is how often the poller should call GetTriggerJson for an RPC
+// subscription. It's meaningless for WS subscriptions, which stay open.
+func (e *EthManager) PollInterval() time.Duration {
+	return e.pollInterval
+}
+
+func (e *EthManager) GetTriggerJson() []byte {
+	if e.backfilling {
+		return e.getBackfillTriggerJson()
+	}
+
+	if e.kind == store.EthSubscriptionKindPendingTx {
+		return e.getPendingTxTriggerJson()
+	}
+
+	if e.p == subscriber.WS {
+		return e.getSubscribeJson()
+	}
+
+	switch e.phase {
+	case filterPhasePoll:
+		return e.getFilterChangesJson()
+	case filterPhaseRecover:
+		return e.getLogsJson()
+	default:
+		return e.getNewFilterJson()
 	}
 }
 
-func (e EthManager) GetTriggerJson() []byte {
-	if e.p == subscriber.RPC && e.fq.FromBlock == "" {
+func (e *EthManager) getSubscribeJson() []byte {
+	filterBytes, err := e.filterBytes()
+	if err != nil {
+		return nil
+	}
+
+	return marshalRpcMessage("eth_subscribe", json.RawMessage(`["logs",`+string(filterBytes)+`]`))
+}
+
+// getLogsJson builds a one-off eth_getLogs call. GetTriggerJson only uses it
+// in filterPhaseRecover, as the gap-filling backfill after a server-side
+// filter has expired.
+func (e *EthManager) getLogsJson() []byte {
+	if e.fq.FromBlock == "" {
 		e.fq.FromBlock = "latest"
 	}
 
-	filter, err := e.fq.toMapInterface()
+	filterBytes, err := e.filterBytes()
+	if err != nil {
+		return nil
+	}
+
+	e.awaitingFilterID = false
+	return marshalRpcMessage("eth_getLogs", json.RawMessage(`[`+string(filterBytes)+`]`))
+}
+
+// getNewFilterJson installs a persistent server-side filter. Its ID is
+// captured from the response by ParseResponse and reused by
+// getFilterChangesJson until the node reports it's gone.
+func (e *EthManager) getNewFilterJson() []byte {
+	if e.fq.FromBlock == "" {
+		e.fq.FromBlock = "latest"
+	}
+
+	filterBytes, err := e.filterBytes()
 	if err != nil {
 		return nil
 	}
 
-	filterBytes, err := json.Marshal(filter)
+	e.awaitingFilterID = true
+	return marshalRpcMessage("eth_newFilter", json.RawMessage(`[`+string(filterBytes)+`]`))
+}
+
+// getFilterChangesJson polls the filter installed by getNewFilterJson for
+// logs that arrived since the last poll.
+func (e *EthManager) getFilterChangesJson() []byte {
+	idBytes, err := json.Marshal(e.filterID)
 	if err != nil {
 		return nil
 	}
 
+	e.awaitingFilterID = false
+	return marshalRpcMessage("eth_getFilterChanges", json.RawMessage(`[`+string(idBytes)+`]`))
+}
+
+// getUninstallFilterJson builds an eth_uninstallFilter request for the
+// filter currently held by this manager, or nil if there's none to tear
+// down.
+func (e *EthManager) getUninstallFilterJson() []byte {
+	if e.p != subscriber.RPC || e.filterID == "" {
+		return nil
+	}
+
+	idBytes, err := json.Marshal(e.filterID)
+	if err != nil {
+		return nil
+	}
+
+	return marshalRpcMessage("eth_uninstallFilter", json.RawMessage(`[`+string(idBytes)+`]`))
+}
+
+// GetUninstallFilterJson builds the eth_uninstallFilter request the poller
+// should send on shutdown, via the Close hook on subscriber.ISubscription.
+// Returns nil if there's no server-side filter to tear down (WS
+// subscriptions, or an RPC subscription that hasn't installed one yet).
+func (e *EthManager) GetUninstallFilterJson() []byte {
+	return e.getUninstallFilterJson()
+}
+
+func (e *EthManager) filterBytes() ([]byte, error) {
+	filter, err := e.fq.toMapInterface()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(filter)
+}
+
+// getPendingTxTriggerJson is the store.EthSubscriptionKindPendingTx
+// counterpart to GetTriggerJson's log-subscription path.
+func (e *EthManager) getPendingTxTriggerJson() []byte {
+	if e.p == subscriber.WS {
+		// fullTx=true so the node sends the entire transaction object,
+		// letting the initiator filter by to/from/selector without a
+		// follow-up eth_getTransactionByHash round trip.
+		return marshalRpcMessage("eth_subscribe", json.RawMessage(`["newPendingTransactions", true]`))
+	}
+
+	if len(e.pendingHashes) > 0 {
+		return e.getTxHydrationJson()
+	}
+
+	switch e.phase {
+	case filterPhasePoll:
+		return e.getFilterChangesJson()
+	default:
+		// RPC has no fullTx variant of eth_newPendingTransactionFilter, so
+		// there's nothing to bridge a filter expiry with; just reinstall.
+		e.phase = filterPhaseInstall
+		e.awaitingFilterID = true
+		return marshalRpcMessage("eth_newPendingTransactionFilter", json.RawMessage(`[]`))
+	}
+}
+
+// getTxHydrationJson builds a batched eth_getTransactionByHash request for
+// the pending-tx hashes eth_getFilterChanges most recently returned, so
+// they can be filtered by the configured predicate before dispatching a job
+// run. Request IDs are the index into pendingHashes;
+// ParseTxHydrationResponse does not read them.
+func (e *EthManager) getTxHydrationJson() []byte {
+	batch := make([]jsonrpcMessage, len(e.pendingHashes))
+	for i, hash := range e.pendingHashes {
+		hashBytes, err := json.Marshal(hash)
+		if err != nil {
+			return nil
+		}
+		idBytes, err := json.Marshal(i)
+		if err != nil {
+			return nil
+		}
+		batch[i] = jsonrpcMessage{
+			Version: "2.0",
+			ID:      idBytes,
+			Method:  "eth_getTransactionByHash",
+			Params:  json.RawMessage(`[` + string(hashBytes) + `]`),
+		}
+	}
+
+	bytes, err := json.Marshal(batch)
+	if err != nil {
+		return nil
+	}
+
+	return bytes
+}
+
+func marshalRpcMessage(method string, params json.RawMessage) []byte {
 	msg := jsonrpcMessage{
 		Version: "2.0",
 		ID:      json.RawMessage(`1`),
-	}
-
-	switch e.p {
-	case subscriber.WS:
-		msg.Method = "eth_subscribe"
-		msg.Params = json.RawMessage(`["logs",` + string(filterBytes) + `]`)
-	case subscriber.RPC:
-		msg.Method = "eth_getLogs"
-		msg.Params = json.RawMessage(`[` + string(filterBytes) + `]`)
+		Method:  method,
+		Params:  params,
 	}
 
 	bytes, err := json.Marshal(msg)
@@ -88,14 +395,95 @@ type ethLogResponse struct {
 	Address          string   `json:"address"`
 	Data             string   `json:"data"`
 	Topics           []string `json:"topics"`
+	Removed          bool     `json:"removed"`
 }
 
-func (e EthManager) ParseResponse(data []byte) ([]subscriber.Event, bool) {
+type ethTxResponse struct {
+	Hash     string `json:"hash"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Input    string `json:"input"`
+	Value    string `json:"value"`
+	Nonce    string `json:"nonce"`
+	Gas      string `json:"gas"`
+	GasPrice string `json:"gasPrice"`
+}
+
+// txPredicate filters pending transactions by "to"/"from" address and/or
+// 4-byte function selector, so the initiator only dispatches a job run for
+// transactions the operator actually cares about. An empty predicate (no
+// addresses and no selectors configured) matches everything.
+type txPredicate struct {
+	addresses map[string]bool
+	selectors map[string]bool
+}
+
+func newTxPredicate(addresses, selectors []string) txPredicate {
+	p := txPredicate{addresses: make(map[string]bool), selectors: make(map[string]bool)}
+	for _, a := range addresses {
+		p.addresses[strings.ToLower(a)] = true
+	}
+	for _, s := range selectors {
+		p.selectors[strings.ToLower(s)] = true
+	}
+	return p
+}
+
+func (p txPredicate) matches(tx ethTxResponse) bool {
+	if len(p.addresses) > 0 {
+		if !p.addresses[strings.ToLower(tx.To)] && !p.addresses[strings.ToLower(tx.From)] {
+			return false
+		}
+	}
+
+	if len(p.selectors) > 0 {
+		if len(tx.Input) < 10 || !p.selectors[strings.ToLower(tx.Input[:10])] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e *EthManager) ParseResponse(data []byte) ([]subscriber.Event, bool) {
+	if e.backfilling {
+		return e.parseBackfillResponse(data)
+	}
+
+	if e.kind == store.EthSubscriptionKindPendingTx {
+		return e.parsePendingTxResponse(data)
+	}
+
 	var msg jsonrpcMessage
 	if err := json.Unmarshal(data, &msg); err != nil {
 		return nil, false
 	}
 
+	if e.p == subscriber.RPC && msg.Error != nil {
+		if isFilterNotFoundError(msg.Error.Message) {
+			// Our filter expired past the node's deadline. Drop the stale
+			// ID and bridge the gap with one eth_getLogs call before
+			// installing a fresh filter on the next poll.
+			e.filterID = ""
+			e.phase = filterPhaseRecover
+			return nil, true
+		}
+		return nil, false
+	}
+
+	if e.p == subscriber.RPC && e.awaitingFilterID {
+		var id string
+		if err := json.Unmarshal(msg.Result, &id); err != nil {
+			return nil, false
+		}
+		e.filterID = id
+		e.phase = filterPhasePoll
+		e.awaitingFilterID = false
+		return nil, true
+	}
+
+	wasRecovering := e.phase == filterPhaseRecover
+
 	var rawEvents []ethLogResponse
 	if err := json.Unmarshal(msg.Result, &rawEvents); err != nil {
 		return nil, false
@@ -103,37 +491,462 @@ func (e EthManager) ParseResponse(data []byte) ([]subscriber.Event, bool) {
 
 	var events []subscriber.Event
 	for _, evt := range rawEvents {
-		if e.p == subscriber.RPC {
-			// Check if we can update the "fromBlock" in the query,
-			// so we only get new events from blocks we haven't queried yet
-			curBlkn, err := strconv.ParseInt(evt.BlockNumber, 0, 64)
-			if err != nil {
-				continue
-			}
-			// Increment the block number by 1, since we want events from *after* this block number
-			curBlkn += 1
+		blockNumber, bnErr := strconv.ParseInt(evt.BlockNumber, 0, 64)
 
-			fromBlkn, err := strconv.ParseInt(e.fq.FromBlock, 0, 64)
-			if err != nil {
+		if e.p == subscriber.RPC {
+			if bnErr != nil {
 				continue
 			}
+			curBlkn := blockNumber
 
-			// If our query "fromBlock" is "latest", or our current "fromBlock" is in the past compared to
-			// the last event we received, we want to update the query
-			if e.fq.FromBlock == "latest" || e.fq.FromBlock == "" || new(big.Int).SetInt64(curBlkn).Cmp(new(big.Int).SetInt64(fromBlkn)) > 0 {
+			if isBlockTag(e.fromTag) && (isBlockTag(e.fq.FromBlock) || e.fq.FromBlock == "") {
+				// FromBlock is a symbolic tag (or unset): we can't compare it
+				// as an int, so just remember the highest block we've seen
+				// and let advanceFromTag decide, once it knows the tag's
+				// current height, whether it's safe to pin FromBlock to a
+				// concrete number.
+				if e.highWaterBlock == nil || e.highWaterBlock.Cmp(big.NewInt(curBlkn)) < 0 {
+					e.highWaterBlock = big.NewInt(curBlkn)
+				}
+				e.advanceFromTag()
+			} else if e.fq.FromBlock == "latest" || e.fq.FromBlock == "" {
+				// No tag configured: pin the plain "latest" query to this
+				// log's block, so a recovery eth_getLogs call starts here.
 				e.fq.FromBlock = evt.BlockNumber
+			} else {
+				// Increment the block number by 1, since we want events from *after* this block number
+				curBlkn += 1
+
+				fromBlkn, err := strconv.ParseInt(e.fq.FromBlock, 0, 64)
+				if err != nil {
+					continue
+				}
+
+				// If our current "fromBlock" is in the past compared to
+				// the last event we received, we want to update the query
+				if new(big.Int).SetInt64(curBlkn).Cmp(new(big.Int).SetInt64(fromBlkn)) > 0 {
+					e.fq.FromBlock = evt.BlockNumber
+				}
 			}
 		}
+
 		event, err := json.Marshal(evt)
 		if err != nil {
 			continue
 		}
+
+		if e.reorgGuard != nil && bnErr == nil {
+			// Buffer instead of emitting straight away: Release below only
+			// lets this out once head has moved minConfirmations blocks
+			// past it, and a canonical replay of a log we've already
+			// delivered is suppressed rather than re-emitted.
+			e.reorgGuard.Buffer(LogKey{BlockHash: evt.BlockHash, LogIndex: evt.LogIndex}, blockNumber, event)
+			e.observeHead(blockNumber)
+			continue
+		}
+
 		events = append(events, event)
 	}
 
+	if e.reorgGuard != nil && e.head != nil {
+		for _, payload := range e.reorgGuard.Release(e.head.Int64()) {
+			events = append(events, payload)
+		}
+	}
+
+	if wasRecovering {
+		// The backfill eth_getLogs call succeeded; go back to installing a
+		// fresh server-side filter on the next poll.
+		e.phase = filterPhaseInstall
+	}
+
 	return events, true
 }
 
+// isFilterNotFoundError reports whether a JSON-RPC error message indicates
+// the node no longer recognizes a filter ID, which happens once a filter
+// goes past the node's deadline (5 minutes on most clients) without being
+// polled via eth_getFilterChanges.
+func isFilterNotFoundError(message string) bool {
+	return strings.Contains(strings.ToLower(message), "filter not found")
+}
+
+// parsePendingTxResponse is the store.EthSubscriptionKindPendingTx
+// counterpart to ParseResponse's log-subscription path.
+func (e *EthManager) parsePendingTxResponse(data []byte) ([]subscriber.Event, bool) {
+	if e.p == subscriber.WS {
+		var msg jsonrpcMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, false
+		}
+
+		var tx ethTxResponse
+		if err := json.Unmarshal(msg.Result, &tx); err != nil {
+			return nil, false
+		}
+
+		return e.emitIfMatching(tx), true
+	}
+
+	if len(e.pendingHashes) > 0 {
+		return e.ParseTxHydrationResponse(data)
+	}
+
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, false
+	}
+
+	if msg.Error != nil {
+		if isFilterNotFoundError(msg.Error.Message) {
+			e.filterID = ""
+			e.phase = filterPhaseInstall
+			return nil, true
+		}
+		return nil, false
+	}
+
+	if e.awaitingFilterID {
+		var id string
+		if err := json.Unmarshal(msg.Result, &id); err != nil {
+			return nil, false
+		}
+		e.filterID = id
+		e.phase = filterPhasePoll
+		e.awaitingFilterID = false
+		return nil, true
+	}
+
+	var hashes []string
+	if err := json.Unmarshal(msg.Result, &hashes); err != nil {
+		return nil, false
+	}
+	e.pendingHashes = hashes
+
+	return nil, true
+}
+
+// ParseTxHydrationResponse parses a batched eth_getTransactionByHash
+// response built from getTxHydrationJson, applies the subscription's
+// predicate, and returns the transactions that matched.
+func (e *EthManager) ParseTxHydrationResponse(data []byte) ([]subscriber.Event, bool) {
+	var batch []jsonrpcMessage
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, false
+	}
+
+	var events []subscriber.Event
+	for _, msg := range batch {
+		if msg.Error != nil || len(msg.Result) == 0 || string(msg.Result) == "null" {
+			continue
+		}
+
+		var tx ethTxResponse
+		if err := json.Unmarshal(msg.Result, &tx); err != nil {
+			continue
+		}
+		events = append(events, e.emitIfMatching(tx)...)
+	}
+
+	e.pendingHashes = nil
+	return events, true
+}
+
+func (e *EthManager) emitIfMatching(tx ethTxResponse) []subscriber.Event {
+	if !e.predicate.matches(tx) {
+		return nil
+	}
+
+	event, err := json.Marshal(tx)
+	if err != nil {
+		return nil
+	}
+
+	return []subscriber.Event{event}
+}
+
+// LastProcessedBlock is the highest block backfill has replayed logs
+// through. The poller should persist this (into
+// store.EthSubscription.LastProcessedBlock) after every ParseResponse call
+// while backfill is running, so a restart mid-replay resumes from here
+// instead of re-scanning from scratch.
+func (e *EthManager) LastProcessedBlock() int64 {
+	return e.lastProcessedBlock
+}
+
+// getBackfillTriggerJson drives the historical replay: first it learns the
+// current head (the upper bound of the replay), then it walks
+// [LastProcessedBlock+1, head] in backfillWindow-sized eth_getLogs batches.
+// Once the cursor passes the target, backfilling is cleared and control
+// falls through to the live WS/RPC path on the next GetTriggerJson call.
+func (e *EthManager) getBackfillTriggerJson() []byte {
+	if e.backfillTarget == nil {
+		return marshalRpcMessage("eth_blockNumber", json.RawMessage(`[]`))
+	}
+
+	if e.backfillCursor > *e.backfillTarget {
+		// Pin the live filter/subscription to start right after the replay
+		// target, not "latest" at install time - otherwise any block mined
+		// while the (serial, potentially slow) backfill was running would
+		// be silently skipped.
+		e.fq.FromBlock = "0x" + big.NewInt(*e.backfillTarget+1).Text(16)
+		e.backfilling = false
+		return e.GetTriggerJson()
+	}
+
+	end := e.backfillCursor + e.backfillWindow - 1
+	if end > *e.backfillTarget {
+		end = *e.backfillTarget
+	}
+	e.backfillWindowEnd = end
+
+	filter := filterQuery{
+		Addresses: e.fq.Addresses,
+		Topics:    e.fq.Topics,
+		FromBlock: "0x" + big.NewInt(e.backfillCursor).Text(16),
+		ToBlock:   "0x" + big.NewInt(end).Text(16),
+	}
+
+	arg, err := filter.toMapInterface()
+	if err != nil {
+		return nil
+	}
+	filterBytes, err := json.Marshal(arg)
+	if err != nil {
+		return nil
+	}
+
+	return marshalRpcMessage("eth_getLogs", json.RawMessage(`[`+string(filterBytes)+`]`))
+}
+
+// parseBackfillResponse is the counterpart to getBackfillTriggerJson.
+func (e *EthManager) parseBackfillResponse(data []byte) ([]subscriber.Event, bool) {
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, false
+	}
+
+	if e.backfillTarget == nil {
+		var hexHeight string
+		if err := json.Unmarshal(msg.Result, &hexHeight); err != nil {
+			return nil, false
+		}
+		height, err := strconv.ParseInt(hexHeight, 0, 64)
+		if err != nil {
+			return nil, false
+		}
+		e.backfillTarget = &height
+		return nil, true
+	}
+
+	if msg.Error != nil {
+		if isTooManyResultsError(msg.Error.Message) && e.backfillWindow > 1 {
+			// Halve the window and retry the same cursor with a smaller
+			// range, rather than giving up on the batch.
+			e.backfillWindow /= 2
+			return nil, true
+		}
+		return nil, false
+	}
+
+	var rawEvents []ethLogResponse
+	if err := json.Unmarshal(msg.Result, &rawEvents); err != nil {
+		return nil, false
+	}
+
+	events := make([]subscriber.Event, 0, len(rawEvents))
+	for _, evt := range rawEvents {
+		event, err := json.Marshal(evt)
+		if err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	e.lastProcessedBlock = e.backfillWindowEnd
+	e.backfillCursor = e.backfillWindowEnd + 1
+
+	return events, true
+}
+
+// isTooManyResultsError reports whether a JSON-RPC error message indicates
+// an eth_getLogs range returned more results than the node is willing to
+// serve in one call (the exact wording varies by client).
+func isTooManyResultsError(message string) bool {
+	lower := strings.ToLower(message)
+	return strings.Contains(lower, "more than") && strings.Contains(lower, "results")
+}
+
+// GetTagHeightJson builds an eth_getBlockByNumber request for this
+// subscription's configured tag (e.g. "finalized"), or nil if the
+// subscription isn't tracking a symbolic tag. The RPC poller should issue
+// this once per poll and feed the response to ParseTagHeightResponse before
+// calling GetTriggerJson for the next eth_getLogs request.
+func (e *EthManager) GetTagHeightJson() []byte {
+	if e.p != subscriber.RPC || !isBlockTag(e.fromTag) {
+		return nil
+	}
+
+	msg := jsonrpcMessage{
+		Version: "2.0",
+		ID:      json.RawMessage(`1`),
+		Method:  "eth_getBlockByNumber",
+		Params:  json.RawMessage(`["` + e.fromTag + `", false]`),
+	}
+
+	bytes, err := json.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+
+	return bytes
+}
+
+// ParseTagHeightResponse records the current height of this subscription's
+// tag, as reported by an eth_getBlockByNumber call built from
+// GetTagHeightJson.
+func (e *EthManager) ParseTagHeightResponse(data []byte) bool {
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return false
+	}
+
+	var blk struct {
+		Number string `json:"number"`
+	}
+	if err := json.Unmarshal(msg.Result, &blk); err != nil {
+		return false
+	}
+
+	height, err := strconv.ParseInt(blk.Number, 0, 64)
+	if err != nil {
+		return false
+	}
+
+	e.tagHeight = big.NewInt(height)
+
+	// Once FromBlock has been pinned to a concrete block number,
+	// per-event tracking in ParseResponse's else branch owns it from
+	// here on - calling advanceFromTag again would recompute it from the
+	// now-stale highWaterBlock and clobber any advances from newer
+	// delivered logs.
+	if isBlockTag(e.fq.FromBlock) || e.fq.FromBlock == "" {
+		e.advanceFromTag()
+	}
+	return true
+}
+
+// advanceFromTag rewrites FromBlock to a concrete hex block number once the
+// highest block we've emitted a log from is at least confirmations below
+// the tag's current height - i.e. once the node itself considers that block
+// settled enough that we no longer need to track it by the (possibly
+// rewinding) symbolic tag. Until then FromBlock is left as the tag, so the
+// next eth_getLogs call keeps asking the node for everything since that tag.
+func (e *EthManager) advanceFromTag() {
+	if e.tagHeight == nil || e.highWaterBlock == nil {
+		return
+	}
+
+	safeHeight := new(big.Int).Sub(e.tagHeight, big.NewInt(e.confirmations))
+	if safeHeight.Cmp(e.highWaterBlock) < 0 {
+		return
+	}
+
+	e.fq.FromBlock = "0x" + new(big.Int).Add(e.highWaterBlock, big.NewInt(1)).Text(16)
+}
+
+// GetHeadBlockJson builds an eth_blockNumber request for the current chain
+// head, or nil if this subscription isn't using a confirmations window (no
+// reorgGuard) and so has no need to track it. The RPC poller should issue
+// this once per poll, alongside GetTriggerJson, and feed the response to
+// ParseHeadBlockResponse so buffered logs in reorgGuard can be released
+// sooner than observeHead's log-derived estimate would get them.
+//
+// There's no WS equivalent: a WS subscription never gets a per-poll chance
+// to send an ad-hoc request, so its head progress comes entirely from
+// observeHead instead.
+func (e *EthManager) GetHeadBlockJson() []byte {
+	if e.p != subscriber.RPC || e.reorgGuard == nil {
+		return nil
+	}
+	return marshalRpcMessage("eth_blockNumber", json.RawMessage(`[]`))
+}
+
+// observeHead advances the manager's notion of chain head from a log's own
+// block number. It only ever increases e.head, so it's a safe (if
+// conservative) lower bound wherever a real head probe - GetHeadBlockJson,
+// RPC-only - isn't available, most importantly over WS: as later logs
+// arrive at higher block numbers, they push head forward and let
+// reorgGuard release what it's buffered for earlier ones.
+func (e *EthManager) observeHead(blockNumber int64) {
+	if e.head == nil || e.head.Cmp(big.NewInt(blockNumber)) < 0 {
+		e.head = big.NewInt(blockNumber)
+	}
+}
+
+// ParseHeadBlockResponse records the chain head height reported by an
+// eth_blockNumber call built from GetHeadBlockJson.
+func (e *EthManager) ParseHeadBlockResponse(data []byte) bool {
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return false
+	}
+
+	var hexHeight string
+	if err := json.Unmarshal(msg.Result, &hexHeight); err != nil {
+		return false
+	}
+
+	height, err := strconv.ParseInt(hexHeight, 0, 64)
+	if err != nil {
+		return false
+	}
+
+	e.head = big.NewInt(height)
+	return true
+}
+
+// GetBlockByHashJson builds an eth_getBlockByHash request, used to check
+// whether a previously delivered log's block is still on the canonical
+// chain.
+func GetBlockByHashJson(blockHash string) []byte {
+	hashBytes, err := json.Marshal(blockHash)
+	if err != nil {
+		return nil
+	}
+	return marshalRpcMessage("eth_getBlockByHash", json.RawMessage(`[`+string(hashBytes)+`, false]`))
+}
+
+// CheckRemovedLogs re-checks every delivered log still held in the
+// reorgGuard's dedup cache against the canonical chain, via getBlockByHash (expected to perform a round trip
+// built from GetBlockByHashJson), and returns a synthetic "removed" event
+// for each one whose block is no longer canonical. The poller should call
+// this periodically, independent of the regular polling cadence.
+func (e *EthManager) CheckRemovedLogs(getBlockByHash func(blockHash string) ([]byte, error)) []subscriber.Event {
+	if e.reorgGuard == nil {
+		return nil
+	}
+
+	removed := e.reorgGuard.CheckRemoved(func(blockHash string) (bool, error) {
+		data, err := getBlockByHash(blockHash)
+		if err != nil {
+			return false, err
+		}
+
+		var msg jsonrpcMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return false, err
+		}
+		return len(msg.Result) > 0 && string(msg.Result) != "null", nil
+	})
+
+	var events []subscriber.Event
+	for _, payload := range removed {
+		events = append(events, payload)
+	}
+	return events
+}
+
 type filterQuery struct {
 	BlockHash *common.Hash     // used by eth_getLogs, return logs only from block with this hash
 	FromBlock string           // beginning of the queried range, nil means genesis block
@@ -180,6 +993,11 @@ type jsonrpcMessage struct {
 	ID      json.RawMessage `json:"id,omitempty"`
 	Method  string          `json:"method,omitempty"`
 	Params  json.RawMessage `json:"params,omitempty"`
-	Error   *interface{}    `json:"error,omitempty"`
+	Error   *jsonrpcError   `json:"error,omitempty"`
 	Result  json.RawMessage `json:"result,omitempty"`
 }
+
+type jsonrpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,156 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartcontractkit/external-initiator/store"
+	"github.com/smartcontractkit/external-initiator/subscriber"
+)
+
+// stubFilterNode models just enough of a geth-style JSON-RPC node to drive
+// the eth_newFilter/eth_getFilterChanges lifecycle: it hands out a filter
+// ID on eth_newFilter, serves canned logs on eth_getFilterChanges, and can
+// be told to start rejecting a filter ID as expired.
+type stubFilterNode struct {
+	nextFilterID int
+	liveFilter   string
+	expired      bool
+	logs         []ethLogResponse
+}
+
+func (s *stubFilterNode) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	var req jsonrpcMessage
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := jsonrpcMessage{Version: "2.0", ID: req.ID}
+
+	switch req.Method {
+	case "eth_newFilter":
+		s.nextFilterID++
+		s.liveFilter = "0xf" + string(rune('0'+s.nextFilterID))
+		s.expired = false
+		id, _ := json.Marshal(s.liveFilter)
+		resp.Result = id
+	case "eth_getFilterChanges":
+		var params []string
+		_ = json.Unmarshal(req.Params, &params)
+		if len(params) != 1 || params[0] != s.liveFilter || s.expired {
+			resp.Error = &jsonrpcError{Code: -32000, Message: "filter not found"}
+		} else {
+			logs, _ := json.Marshal(s.logs)
+			resp.Result = logs
+			s.logs = nil
+		}
+	case "eth_getLogs":
+		logs, _ := json.Marshal(s.logs)
+		resp.Result = logs
+		s.logs = nil
+	default:
+		http.Error(w, "unexpected method "+req.Method, http.StatusBadRequest)
+		return
+	}
+
+	out, _ := json.Marshal(resp)
+	w.Write(out)
+}
+
+func (s *stubFilterNode) post(t *testing.T, url string, reqBytes []byte) []byte {
+	t.Helper()
+	res, err := http.Post(url, "application/json", bytes.NewReader(reqBytes))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	return body
+}
+
+func TestEthManager_FilterLifecycle(t *testing.T) {
+	node := &stubFilterNode{logs: []ethLogResponse{{BlockNumber: "0x1", BlockHash: "0xabc"}}}
+	server := httptest.NewServer(http.HandlerFunc(node.handle))
+	defer server.Close()
+
+	e := CreateEthManager(subscriber.RPC, store.EthSubscription{})
+
+	// First poll installs a filter.
+	req := e.GetTriggerJson()
+	res := node.post(t, server.URL, req)
+	events, ok := e.ParseResponse(res)
+	if !ok {
+		t.Fatalf("ParseResponse failed on filter install")
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no events from the install response, got %d", len(events))
+	}
+	if e.filterID == "" {
+		t.Fatalf("expected a filter ID to be captured")
+	}
+
+	// Second poll delivers logs via eth_getFilterChanges.
+	req = e.GetTriggerJson()
+	res = node.post(t, server.URL, req)
+	events, ok = e.ParseResponse(res)
+	if !ok || len(events) != 1 {
+		t.Fatalf("expected 1 event via eth_getFilterChanges, got %d (ok=%v)", len(events), ok)
+	}
+
+	// Node now reports the filter as expired past its deadline.
+	node.expired = true
+	node.logs = []ethLogResponse{{BlockNumber: "0x2", BlockHash: "0xdef"}}
+
+	req = e.GetTriggerJson()
+	res = node.post(t, server.URL, req)
+	events, ok = e.ParseResponse(res)
+	if !ok {
+		t.Fatalf("ParseResponse failed on filter-not-found")
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no events on the filter-not-found response, got %d", len(events))
+	}
+	if e.filterID != "" {
+		t.Fatalf("expected filter ID to be cleared after filter-not-found")
+	}
+
+	// Next poll should bridge the gap with eth_getLogs...
+	req = e.GetTriggerJson()
+	res = node.post(t, server.URL, req)
+	events, ok = e.ParseResponse(res)
+	if !ok || len(events) != 1 {
+		t.Fatalf("expected 1 backfilled event via eth_getLogs, got %d (ok=%v)", len(events), ok)
+	}
+
+	// ...and the poll after that should install a fresh filter again.
+	req = e.GetTriggerJson()
+	res = node.post(t, server.URL, req)
+	if _, ok = e.ParseResponse(res); !ok {
+		t.Fatalf("ParseResponse failed on filter re-install")
+	}
+	if e.filterID == "" {
+		t.Fatalf("expected a new filter ID after recovery")
+	}
+}
+
+func TestEthManager_GetUninstallFilterJson(t *testing.T) {
+	e := CreateEthManager(subscriber.RPC, store.EthSubscription{})
+
+	if got := e.GetUninstallFilterJson(); got != nil {
+		t.Fatalf("expected nil uninstall request before a filter exists, got %s", got)
+	}
+
+	e.filterID = "0xf1"
+	if got := e.GetUninstallFilterJson(); got == nil {
+		t.Fatalf("expected an uninstall request once a filter exists")
+	}
+}
@@ -0,0 +1,86 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/external-initiator/store"
+	"github.com/smartcontractkit/external-initiator/subscriber"
+)
+
+func TestEthManager_PendingTxWS_FiltersBySelector(t *testing.T) {
+	e := CreateEthManager(subscriber.WS, store.EthSubscription{
+		Kind:      store.EthSubscriptionKindPendingTx,
+		Selectors: []string{"0xa9059cbb"},
+	})
+
+	match := mustMarshalRpcResult(t, ethTxResponse{Hash: "0x1", Input: "0xa9059cbb00000000"})
+	events, ok := e.ParseResponse(match)
+	if !ok || len(events) != 1 {
+		t.Fatalf("expected matching tx to be emitted, got %d events (ok=%v)", len(events), ok)
+	}
+
+	noMatch := mustMarshalRpcResult(t, ethTxResponse{Hash: "0x2", Input: "0x12345678"})
+	events, ok = e.ParseResponse(noMatch)
+	if !ok || len(events) != 0 {
+		t.Fatalf("expected non-matching tx to be dropped, got %d events (ok=%v)", len(events), ok)
+	}
+}
+
+func TestEthManager_PendingTxRPC_HydratesHashes(t *testing.T) {
+	e := CreateEthManager(subscriber.RPC, store.EthSubscription{
+		Kind:      store.EthSubscriptionKindPendingTx,
+		Addresses: []string{"0xAAA0000000000000000000000000000000000A"},
+	})
+
+	// Install the filter.
+	req := e.GetTriggerJson()
+	if req == nil {
+		t.Fatalf("expected an eth_newPendingTransactionFilter request")
+	}
+	if _, ok := e.ParseResponse(mustMarshalRpcResult(t, "0xf1")); !ok {
+		t.Fatalf("failed to parse filter install response")
+	}
+
+	// Poll returns new pending tx hashes.
+	req = e.GetTriggerJson()
+	if req == nil {
+		t.Fatalf("expected an eth_getFilterChanges request")
+	}
+	hashesJSON, _ := json.Marshal([]string{"0xdeadbeef"})
+	if _, ok := e.ParseResponse(wrapRpcResult(hashesJSON)); !ok {
+		t.Fatalf("failed to parse filter changes response")
+	}
+
+	// Next trigger should be the batched hydration call.
+	req = e.GetTriggerJson()
+	var batch []jsonrpcMessage
+	if err := json.Unmarshal(req, &batch); err != nil {
+		t.Fatalf("expected a batched eth_getTransactionByHash request: %v", err)
+	}
+	if len(batch) != 1 || batch[0].Method != "eth_getTransactionByHash" {
+		t.Fatalf("unexpected hydration batch: %+v", batch)
+	}
+
+	txJSON, _ := json.Marshal(ethTxResponse{Hash: "0xdeadbeef", To: "0xaaa0000000000000000000000000000000000a"})
+	resp, _ := json.Marshal([]jsonrpcMessage{{Version: "2.0", ID: json.RawMessage(`0`), Result: txJSON}})
+	events, ok := e.ParseTxHydrationResponse(resp)
+	if !ok || len(events) != 1 {
+		t.Fatalf("expected 1 hydrated event, got %d (ok=%v)", len(events), ok)
+	}
+}
+
+func mustMarshalRpcResult(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	result, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return wrapRpcResult(result)
+}
+
+func wrapRpcResult(result json.RawMessage) []byte {
+	msg := jsonrpcMessage{Version: "2.0", ID: json.RawMessage(`1`), Result: result}
+	out, _ := json.Marshal(msg)
+	return out
+}
@@ -0,0 +1,136 @@
+package blockchain
+
+import "encoding/json"
+
+// defaultDedupCacheSize bounds how many delivered logs a ReorgGuard keeps
+// around for replay suppression and removed-event detection.
+const defaultDedupCacheSize = 2048
+
+// LogKey identifies a log uniquely enough to dedup it across polls and
+// reorgs: the block it landed in, plus its index within that block.
+type LogKey struct {
+	BlockHash string
+	LogIndex  string
+}
+
+type bufferedLog struct {
+	key         LogKey
+	blockNumber int64
+	payload     []byte
+}
+
+type deliveredLog struct {
+	blockHash string
+	payload   []byte
+}
+
+// ReorgGuard buffers logs behind a confirmations window and suppresses
+// duplicate or reorged-out deliveries. It only deals in opaque JSON
+// payloads plus the minimal (LogKey, block number) identifying information
+// a caller hands it, so it has no Ethereum-specific knowledge and can be
+// embedded by any chain manager, not just EthManager.
+type ReorgGuard struct {
+	minConfirmations int64
+	pending          []bufferedLog
+	delivered        map[LogKey]deliveredLog
+	deliveredOrder   []LogKey
+}
+
+// NewReorgGuard creates a guard that releases a buffered log once the chain
+// head is at least minConfirmations blocks past it.
+func NewReorgGuard(minConfirmations int64) *ReorgGuard {
+	return &ReorgGuard{
+		minConfirmations: minConfirmations,
+		delivered:        make(map[LogKey]deliveredLog),
+	}
+}
+
+// Buffer queues a log for release once the head has advanced far enough
+// past it. A log whose key has already been delivered, or is already
+// pending, is dropped - it's a canonical replay of one we've handled.
+func (g *ReorgGuard) Buffer(key LogKey, blockNumber int64, payload []byte) {
+	if _, ok := g.delivered[key]; ok {
+		return
+	}
+	for _, p := range g.pending {
+		if p.key == key {
+			return
+		}
+	}
+	g.pending = append(g.pending, bufferedLog{key: key, blockNumber: blockNumber, payload: payload})
+}
+
+// Release returns the payloads of every buffered log at least
+// minConfirmations blocks behind head, and records them as delivered so a
+// later reorg replay of the same log is suppressed rather than re-emitted.
+func (g *ReorgGuard) Release(head int64) [][]byte {
+	var ready [][]byte
+	var still []bufferedLog
+	for _, p := range g.pending {
+		if head-p.blockNumber >= g.minConfirmations {
+			ready = append(ready, p.payload)
+			g.markDelivered(p.key, p.payload)
+		} else {
+			still = append(still, p)
+		}
+	}
+	g.pending = still
+	return ready
+}
+
+func (g *ReorgGuard) markDelivered(key LogKey, payload []byte) {
+	g.delivered[key] = deliveredLog{blockHash: key.BlockHash, payload: payload}
+	g.deliveredOrder = append(g.deliveredOrder, key)
+	if len(g.deliveredOrder) > defaultDedupCacheSize {
+		oldest := g.deliveredOrder[0]
+		g.deliveredOrder = g.deliveredOrder[1:]
+		delete(g.delivered, oldest)
+	}
+}
+
+// CheckRemoved re-checks every delivered log still held in the dedup cache
+// against the canonical chain, via the caller-supplied isCanonical (e.g.
+// backed by a periodic eth_getBlockByHash call), and returns a synthetic
+// "removed" event - mirroring geth's removed:true field - for each one
+// that's no longer on it. Checked-out entries are dropped from the cache so
+// they aren't re-checked on the next call.
+func (g *ReorgGuard) CheckRemoved(isCanonical func(blockHash string) (bool, error)) [][]byte {
+	var removed [][]byte
+	for key, dl := range g.delivered {
+		canonical, err := isCanonical(dl.blockHash)
+		if err != nil || canonical {
+			continue
+		}
+		if marked, ok := markRemoved(dl.payload); ok {
+			removed = append(removed, marked)
+		}
+		delete(g.delivered, key)
+		g.removeFromOrder(key)
+	}
+	return removed
+}
+
+func (g *ReorgGuard) removeFromOrder(key LogKey) {
+	for i, k := range g.deliveredOrder {
+		if k == key {
+			g.deliveredOrder = append(g.deliveredOrder[:i], g.deliveredOrder[i+1:]...)
+			return
+		}
+	}
+}
+
+// markRemoved sets "removed": true on an opaque JSON log payload without
+// needing to know its full schema.
+func markRemoved(payload []byte) ([]byte, bool) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, false
+	}
+	fields["removed"] = true
+
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
@@ -0,0 +1,107 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartcontractkit/external-initiator/store"
+	"github.com/smartcontractkit/external-initiator/subscriber"
+)
+
+func TestReorgGuard_ReleaseAfterConfirmations(t *testing.T) {
+	g := NewReorgGuard(3)
+
+	key := LogKey{BlockHash: "0xabc", LogIndex: "0x0"}
+	g.Buffer(key, 10, []byte(`{"blockHash":"0xabc"}`))
+
+	if out := g.Release(11); len(out) != 0 {
+		t.Fatalf("expected log to stay buffered below the confirmations window, got %d", len(out))
+	}
+
+	out := g.Release(13)
+	if len(out) != 1 {
+		t.Fatalf("expected 1 released log once confirmations are met, got %d", len(out))
+	}
+}
+
+func TestReorgGuard_SuppressesReplay(t *testing.T) {
+	g := NewReorgGuard(0)
+	key := LogKey{BlockHash: "0xabc", LogIndex: "0x0"}
+
+	g.Buffer(key, 10, []byte(`{}`))
+	g.Release(10)
+
+	// A reorg replays the same log on the canonical branch.
+	g.Buffer(key, 10, []byte(`{}`))
+	if out := g.Release(10); len(out) != 0 {
+		t.Fatalf("expected replayed log to be suppressed, got %d", len(out))
+	}
+}
+
+func TestReorgGuard_CheckRemoved(t *testing.T) {
+	g := NewReorgGuard(0)
+	key := LogKey{BlockHash: "0xabc", LogIndex: "0x0"}
+
+	g.Buffer(key, 10, []byte(`{"blockHash":"0xabc","data":"0x1"}`))
+	g.Release(10)
+
+	removed := g.CheckRemoved(func(blockHash string) (bool, error) {
+		return blockHash != "0xabc", nil
+	})
+	if len(removed) != 1 {
+		t.Fatalf("expected 1 removed event, got %d", len(removed))
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(removed[0], &fields); err != nil {
+		t.Fatalf("unmarshal removed event: %v", err)
+	}
+	if fields["removed"] != true {
+		t.Fatalf("expected removed:true, got %v", fields["removed"])
+	}
+
+	// Already checked out of the dedup cache, so it shouldn't be reported again.
+	if out := g.CheckRemoved(func(string) (bool, error) { return false, nil }); len(out) != 0 {
+		t.Fatalf("expected no further removed events, got %d", len(out))
+	}
+}
+
+// TestEthManager_WS_ConfirmationsReleaseWithoutHeadProbe exercises
+// MinConfirmations end-to-end through EthManager.ParseResponse over WS,
+// where there's no eth_blockNumber poll to learn head from (GetHeadBlockJson
+// is RPC-only) - head progress has to come from the logs themselves.
+func TestEthManager_WS_ConfirmationsReleaseWithoutHeadProbe(t *testing.T) {
+	e := CreateEthManager(subscriber.WS, store.EthSubscription{MinConfirmations: 2})
+
+	events, ok := e.ParseResponse(wrapRpcResult(mustMarshal(t, []ethLogResponse{{
+		BlockNumber: "0xa", BlockHash: "0xaaa", LogIndex: "0x0",
+	}})))
+	if !ok || len(events) != 0 {
+		t.Fatalf("expected block 0xa to stay buffered, got %d events (ok=%v)", len(events), ok)
+	}
+
+	// A log 1 block later isn't enough confirmations yet.
+	events, ok = e.ParseResponse(wrapRpcResult(mustMarshal(t, []ethLogResponse{{
+		BlockNumber: "0xb", BlockHash: "0xbbb", LogIndex: "0x0",
+	}})))
+	if !ok || len(events) != 0 {
+		t.Fatalf("expected nothing released yet, got %d events (ok=%v)", len(events), ok)
+	}
+
+	// A log 2 blocks later satisfies MinConfirmations for block 0xa, but
+	// not yet for 0xb.
+	events, ok = e.ParseResponse(wrapRpcResult(mustMarshal(t, []ethLogResponse{{
+		BlockNumber: "0xc", BlockHash: "0xccc", LogIndex: "0x0",
+	}})))
+	if !ok || len(events) != 1 {
+		t.Fatalf("expected exactly the 0xa log to release once head derived from logs reaches 0xc, got %d (ok=%v)", len(events), ok)
+	}
+
+	var released ethLogResponse
+	if err := json.Unmarshal(events[0], &released); err != nil {
+		t.Fatalf("unmarshal released event: %v", err)
+	}
+	if released.BlockNumber != "0xa" {
+		t.Fatalf("expected the released log to be from block 0xa, got %s", released.BlockNumber)
+	}
+}
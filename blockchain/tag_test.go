@@ -0,0 +1,67 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/smartcontractkit/external-initiator/store"
+	"github.com/smartcontractkit/external-initiator/subscriber"
+)
+
+// TestEthManager_TagPin_SurvivesLaterTagHeightPolls drives one full
+// FromTag/TagSettleConfirmations poll cycle: the tag settles and FromBlock
+// gets pinned to a concrete block number, then normal per-event tracking
+// advances it further, and a subsequent GetTagHeightJson/ParseTagHeightResponse
+// poll (which the RPC poller keeps issuing every cycle per GetTagHeightJson's
+// doc comment) must not claw FromBlock back down from its now-stale
+// highWaterBlock.
+func TestEthManager_TagPin_SurvivesLaterTagHeightPolls(t *testing.T) {
+	e := CreateEthManager(subscriber.RPC, store.EthSubscription{
+		FromTag:                "finalized",
+		TagSettleConfirmations: 5,
+	})
+
+	if e.fq.FromBlock != "finalized" {
+		t.Fatalf("expected FromBlock to start pinned to the tag, got %q", e.fq.FromBlock)
+	}
+
+	// First tag height poll: 100. Nothing emitted yet, so highWaterBlock is
+	// still nil and advanceFromTag is a no-op.
+	if !e.ParseTagHeightResponse(mustMarshalRpcResult(t, blockByNumberResult{Number: "0x64"})) {
+		t.Fatalf("failed to parse tag height response")
+	}
+	if e.fq.FromBlock != "finalized" {
+		t.Fatalf("expected FromBlock to stay on the tag before any log settles it, got %q", e.fq.FromBlock)
+	}
+
+	// A log at block 90 is 10 blocks below the tag's height (100), well past
+	// the 5-block settle window - this pins FromBlock to a concrete number.
+	if _, ok := e.ParseResponse(wrapRpcResult(mustMarshal(t, []ethLogResponse{{BlockNumber: "0x5a"}}))); !ok {
+		t.Fatalf("failed to parse log response")
+	}
+	if e.fq.FromBlock != "0x5b" {
+		t.Fatalf("expected FromBlock to pin to 0x5b once settled, got %q", e.fq.FromBlock)
+	}
+
+	// A later log at block 94 advances FromBlock further via the normal
+	// (non-tag) tracking path.
+	if _, ok := e.ParseResponse(wrapRpcResult(mustMarshal(t, []ethLogResponse{{BlockNumber: "0x5e"}}))); !ok {
+		t.Fatalf("failed to parse log response")
+	}
+	if e.fq.FromBlock != "0x5e" {
+		t.Fatalf("expected FromBlock to advance to 0x5e from the new log, got %q", e.fq.FromBlock)
+	}
+
+	// The poller keeps polling GetTagHeightJson/ParseTagHeightResponse every
+	// cycle regardless - this must not reset FromBlock back down to the
+	// stale highWaterBlock-derived pin.
+	if !e.ParseTagHeightResponse(mustMarshalRpcResult(t, blockByNumberResult{Number: "0x64"})) {
+		t.Fatalf("failed to parse second tag height response")
+	}
+	if e.fq.FromBlock != "0x5e" {
+		t.Fatalf("expected FromBlock to remain at 0x5e, got %q (reverted by a stale advanceFromTag call)", e.fq.FromBlock)
+	}
+}
+
+type blockByNumberResult struct {
+	Number string `json:"number"`
+}
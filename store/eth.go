@@ -0,0 +1,77 @@
+package store
+
+import "time"
+
+// EthSubscriptionKind discriminates what an EthSubscription triggers a job
+// run on.
+type EthSubscriptionKind string
+
+const (
+	// EthSubscriptionKindLogs triggers on logs matching Addresses/Topics.
+	// This is the default, for subscriptions that don't set Kind.
+	EthSubscriptionKindLogs EthSubscriptionKind = "logs"
+	// EthSubscriptionKindPendingTx triggers on pending transactions
+	// matching Addresses/Selectors.
+	EthSubscriptionKindPendingTx EthSubscriptionKind = "pending"
+)
+
+// EthSubscription holds the operator-configured parameters for an Ethereum
+// subscription.
+type EthSubscription struct {
+	// Kind selects what this subscription triggers on. Empty defaults to
+	// EthSubscriptionKindLogs.
+	Kind EthSubscriptionKind
+
+	// Addresses restricts matches to these contract addresses for
+	// EthSubscriptionKindLogs, or these "to"/"from" addresses for
+	// EthSubscriptionKindPendingTx. Empty matches any address.
+	Addresses []string
+	Topics    []string
+	// Selectors restricts EthSubscriptionKindPendingTx matches to
+	// transactions whose input data starts with one of these 4-byte
+	// function selectors (e.g. "0xa9059cbb"). Empty matches any selector.
+	Selectors []string
+
+	// FromTag pins the subscription's FromBlock to a named block tag
+	// ("accepted", "finalized", "safe") instead of a concrete block number,
+	// so the manager keeps following the tag as the chain head advances.
+	FromTag string
+	// TagSettleConfirmations is how many blocks below FromTag's reported
+	// height a block must be before the manager will stop tracking it via
+	// the tag and pin FromBlock to that block's concrete number. Only
+	// meaningful when FromTag is set.
+	//
+	// This is unrelated to MinConfirmations below: that one gates when a
+	// log is released to the job runner, this one gates when the manager
+	// stops following FromTag and settles on a concrete block number.
+	TagSettleConfirmations int64
+
+	// PollInterval overrides how often an RPC subscription polls for new
+	// data (via eth_getFilterChanges). Zero uses the manager's default.
+	PollInterval time.Duration
+
+	// MinConfirmations is how many blocks must pass on top of a log's
+	// block before it's released to the job runner, and the window within
+	// which a reorg replaying or dropping that log is caught. Zero (the
+	// default) emits logs as soon as they're seen, with no reorg
+	// protection.
+	//
+	// This is unrelated to TagSettleConfirmations above: that one gates
+	// when the manager stops following FromTag, this one gates log
+	// delivery itself.
+	MinConfirmations int64
+
+	// LastProcessedBlock is the last block this subscription successfully
+	// delivered logs through, persisted after every backfill batch so a
+	// restart resumes the historical replay instead of re-scanning from
+	// scratch or skipping straight to "latest". Zero means there's nothing
+	// to replay - either a brand new subscription, or one whose operator
+	// doesn't want backfill.
+	LastProcessedBlock int64
+
+	// BackfillBatchSize is how many blocks a single eth_getLogs backfill
+	// call covers. Zero uses the manager's default. Halved automatically,
+	// down to a floor of 1, if a node rejects a batch for returning too
+	// many results.
+	BackfillBatchSize int64
+}
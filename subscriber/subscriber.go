@@ -0,0 +1,22 @@
+package subscriber
+
+// Type identifies the transport a Manager uses to talk to a chain node.
+type Type int
+
+const (
+	WS Type = iota
+	RPC
+)
+
+// Event is a single raw payload handed off to the Chainlink node for a job run.
+type Event []byte
+
+// ISubscription is returned by a Manager once it has started listening, and
+// lets the caller tear down any server-side state the subscription holds.
+type ISubscription interface {
+	Unsubscribe()
+	// Close uninstalls any server-side state (e.g. an RPC filter) before
+	// the subscription is torn down, so the node can free it immediately
+	// instead of waiting for it to expire on its own.
+	Close()
+}